@@ -2,63 +2,105 @@ package easycel
 
 import (
 	"fmt"
+	"maps"
 	"reflect"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/cel-go/cel"
 	"github.com/google/cel-go/common/types"
 	"github.com/google/cel-go/common/types/ref"
-	exprpb "google.golang.org/genproto/googleapis/api/expr/v1alpha1"
+	"github.com/google/cel-go/common/types/traits"
 )
 
 type Registry struct {
 	nativeTypeProvider *nativeTypeProvider
 	funcs              map[string][]cel.FunctionOpt
 	variables          map[string]*cel.Type
-	registry           ref.TypeRegistry
-	adapter            ref.TypeAdapter
-	provider           ref.TypeProvider
-	tagName            string
+	registry           *types.Registry
+	adapter            types.Adapter
+	provider           types.Provider
+	tagNames           []string
 	libraryName        string
+
+	// interfaceImpls maps an interface type to its registered concrete
+	// implementations, keyed by each implementation's type discriminator.
+	// interfaceNames/interfaceByName is the CEL object type name registered
+	// for that interface, in both directions. All three are guarded by
+	// interfaceMu: RegisterInterface writes them, while convertToCelType and
+	// the CompileOptions-registered provider methods read them, and a single
+	// Registry may be shared across concurrently compiling environments.
+	interfaceImpls  map[reflect.Type]map[string]reflect.Type
+	interfaceNames  map[reflect.Type]string
+	interfaceByName map[string]reflect.Type
+	interfaceMu     sync.RWMutex
+
+	// arrayFields records, per registered struct CEL type name, the fields
+	// that are fixed-size Go arrays, so NewValue can validate list length.
+	arrayFields   map[string]map[string]reflect.Type
+	arrayFieldsMu sync.RWMutex
+}
+
+// typeDiscriminator is implemented by interface implementations that
+// identify their concrete type explicitly, instead of via a tagged field.
+type typeDiscriminator interface {
+	TypeByte() string
 }
 
+var typeDiscriminatorType = reflect.TypeOf((*typeDiscriminator)(nil)).Elem()
+
 type RegistryOption func(*Registry)
 
 // WithTypeAdapter sets the type adapter used to convert types to CEL types.
-func WithTypeAdapter(adapter ref.TypeAdapter) RegistryOption {
+func WithTypeAdapter(adapter types.Adapter) RegistryOption {
 	return func(r *Registry) {
 		r.adapter = adapter
 	}
 }
 
 // WithTypeProvider sets the type provider used to convert types to CEL types.
-func WithTypeProvider(provider ref.TypeProvider) RegistryOption {
+func WithTypeProvider(provider types.Provider) RegistryOption {
 	return func(r *Registry) {
 		r.provider = provider
 	}
 }
 
 // WithTagName sets the tag name used to convert types to CEL types.
+//
+// Deprecated: use WithTagNames to configure a fallback chain of tag names.
 func WithTagName(tagName string) RegistryOption {
+	return WithTagNames(tagName)
+}
+
+// WithTagNames sets the fallback chain of tag names used to resolve a
+// struct field's CEL-visible name and options: each field is checked against
+// tagNames in order and the first one present wins, falling back to the
+// field's Go name if none match (e.g. WithTagNames("easycel", "json")).
+func WithTagNames(tagNames ...string) RegistryOption {
 	return func(r *Registry) {
-		r.tagName = tagName
+		r.tagNames = tagNames
 	}
 }
 
 // NewRegistry creates adapter new Registry.
 func NewRegistry(libraryName string, opts ...RegistryOption) *Registry {
 	r := &Registry{
-		funcs:       make(map[string][]cel.FunctionOpt),
-		variables:   make(map[string]*cel.Type),
-		tagName:     "easycel",
-		libraryName: libraryName,
+		funcs:           make(map[string][]cel.FunctionOpt),
+		variables:       make(map[string]*cel.Type),
+		interfaceImpls:  make(map[reflect.Type]map[string]reflect.Type),
+		interfaceNames:  make(map[reflect.Type]string),
+		interfaceByName: make(map[string]reflect.Type),
+		arrayFields:     make(map[string]map[string]reflect.Type),
+		tagNames:        []string{"easycel"},
+		libraryName:     libraryName,
 	}
 	for _, opt := range opts {
 		opt(r)
 	}
 	registry, _ := types.NewRegistry()
-	tp := newNativeTypeProvider(r.tagName, registry, registry)
+	tp := newNativeTypeProvider(r.tagNames, registry, registry)
 	if r.adapter == nil {
 		r.adapter = tp
 	}
@@ -107,21 +149,175 @@ func (r *Registry) FindIdent(identName string) (ref.Val, bool) {
 	return r.provider.FindIdent(identName)
 }
 
-// FindType looks up the Type given adapter qualified typeName.
-func (r *Registry) FindType(typeName string) (*exprpb.Type, bool) {
-	return r.provider.FindType(typeName)
+// FindStructType looks up the Type given adapter qualified typeName.
+func (r *Registry) FindStructType(structType string) (*types.Type, bool) {
+	r.interfaceMu.RLock()
+	_, isInterface := r.interfaceByName[structType]
+	r.interfaceMu.RUnlock()
+	if isInterface {
+		return types.NewObjectType(structType), true
+	}
+	return r.provider.FindStructType(structType)
+}
+
+// FindStructFieldType returns the field type for adapter checked type value.
+// When structType names a registered interface, the field type is resolved
+// by checking each of its concrete implementations in turn, so a field
+// access on an interface-typed value type-checks as long as every
+// implementation agrees on that field's type.
+func (r *Registry) FindStructFieldType(structType string, fieldName string) (*types.FieldType, bool) {
+	r.interfaceMu.RLock()
+	ifaceType, isInterface := r.interfaceByName[structType]
+	r.interfaceMu.RUnlock()
+	if isInterface {
+		return r.findInterfaceFieldType(ifaceType, fieldName)
+	}
+	return r.provider.FindStructFieldType(structType, fieldName)
+}
+
+// findInterfaceFieldType resolves fieldName against every concrete
+// implementation registered for ifaceType, returning it only if all of them
+// have the field and agree on its type. A field that's missing from one
+// implementation, or typed differently across implementations, is rejected
+// here so it fails to type-check instead of panicking at evaluation time
+// against whichever implementation lacks it.
+func (r *Registry) findInterfaceFieldType(ifaceType reflect.Type, fieldName string) (*types.FieldType, bool) {
+	r.interfaceMu.RLock()
+	impls := r.interfaceImpls[ifaceType]
+	r.interfaceMu.RUnlock()
+	if len(impls) == 0 {
+		return nil, false
+	}
+
+	var agreed *types.FieldType
+	for _, implType := range impls {
+		fieldType, ok := r.provider.FindStructFieldType(typeName(implType), fieldName)
+		if !ok {
+			return nil, false
+		}
+		if agreed == nil {
+			agreed = fieldType
+			continue
+		}
+		if agreed.Type.String() != fieldType.Type.String() {
+			return nil, false
+		}
+	}
+	return agreed, true
+}
+
+// FindStructFieldNames returns the field names defined for adapter qualified
+// type name.
+func (r *Registry) FindStructFieldNames(structType string) ([]string, bool) {
+	r.interfaceMu.RLock()
+	ifaceType, isInterface := r.interfaceByName[structType]
+	r.interfaceMu.RUnlock()
+	if isInterface {
+		return r.findInterfaceFieldNames(ifaceType)
+	}
+	return r.provider.FindStructFieldNames(structType)
 }
 
-// FindFieldType returns the field type for adapter checked type value.
-func (r *Registry) FindFieldType(messageType string, fieldName string) (*ref.FieldType, bool) {
-	return r.provider.FindFieldType(messageType, fieldName)
+// findInterfaceFieldNames returns the union of field names across every
+// concrete implementation registered for ifaceType. Unlike
+// findInterfaceFieldType, it doesn't require every implementation to agree:
+// it's a name listing, not a type check, so it's fine for a name to only be
+// valid on some implementations.
+func (r *Registry) findInterfaceFieldNames(ifaceType reflect.Type) ([]string, bool) {
+	r.interfaceMu.RLock()
+	impls := r.interfaceImpls[ifaceType]
+	r.interfaceMu.RUnlock()
+	if len(impls) == 0 {
+		return nil, false
+	}
+
+	seen := make(map[string]bool)
+	var names []string
+	for _, implType := range impls {
+		implNames, ok := r.provider.FindStructFieldNames(typeName(implType))
+		if !ok {
+			continue
+		}
+		for _, name := range implNames {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	return names, true
 }
 
 // NewValue creates adapter new type value from adapter qualified name and map of field name to value.
 func (r *Registry) NewValue(typeName string, fields map[string]ref.Val) ref.Val {
+	r.interfaceMu.RLock()
+	ifaceType, isInterface := r.interfaceByName[typeName]
+	r.interfaceMu.RUnlock()
+	if isInterface {
+		return r.newInterfaceValue(ifaceType, fields)
+	}
+	if err := r.validateArrayFields(typeName, fields); err != nil {
+		return types.WrapErr(err)
+	}
 	return r.provider.NewValue(typeName, fields)
 }
 
+// interfaceDiscriminatorField is the reserved fields key NewValue uses to
+// resolve which concrete implementation of a registered interface to
+// construct, set to the discriminator identifying that value's concrete type.
+const interfaceDiscriminatorField = "type"
+
+// newInterfaceValue resolves the concrete implementation of ifaceType named
+// by the reserved "type" discriminator field and delegates construction to
+// the underlying provider.
+func (r *Registry) newInterfaceValue(ifaceType reflect.Type, fields map[string]ref.Val) ref.Val {
+	discriminatorVal, ok := fields[interfaceDiscriminatorField]
+	if !ok {
+		return types.NewErr("cannot construct %s: missing discriminator field %q", typeName(ifaceType), interfaceDiscriminatorField)
+	}
+	discriminator, ok := discriminatorVal.Value().(string)
+	if !ok {
+		return types.NewErr("discriminator field %q must be a string", interfaceDiscriminatorField)
+	}
+	implType, ok := r.FindInterfaceImplType(ifaceType, discriminator)
+	if !ok {
+		return types.NewErr("%s has no implementation registered for discriminator %q", typeName(ifaceType), discriminator)
+	}
+	// The discriminator is a reserved key for interface dispatch, not a real
+	// field on implType, so it must not reach the underlying provider.
+	implFields := maps.Clone(fields)
+	delete(implFields, interfaceDiscriminatorField)
+	if err := r.validateArrayFields(typeName(implType), implFields); err != nil {
+		return types.WrapErr(err)
+	}
+	return r.provider.NewValue(typeName(implType), implFields)
+}
+
+// validateArrayFields checks that every fixed-size array field registered
+// for typeName is, if present in fields and bound to a CEL list, bound to a
+// list of exactly the array's declared length. Values that are not CEL lists
+// (e.g. bytes bound to a byte array field) are not length-checked.
+func (r *Registry) validateArrayFields(typeName string, fields map[string]ref.Val) error {
+	r.arrayFieldsMu.RLock()
+	arrFields := r.arrayFields[typeName]
+	r.arrayFieldsMu.RUnlock()
+	for fieldName, arrType := range arrFields {
+		val, ok := fields[fieldName]
+		if !ok {
+			continue
+		}
+		lister, ok := val.(traits.Lister)
+		if !ok {
+			continue
+		}
+		size := int(lister.Size().(types.Int))
+		if err := validateArrayLength(fieldName, arrType, size); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // ProgramOptions implements the Library interface method.
 func (r *Registry) ProgramOptions() []cel.ProgramOption {
 	return []cel.ProgramOption{}
@@ -135,16 +331,128 @@ func (r *Registry) RegisterType(refTyes any) error {
 	case ref.Type:
 		return r.registry.RegisterType(v)
 	}
+	structType := reflect.TypeOf(refTyes)
+	for structType.Kind() == reflect.Pointer {
+		structType = structType.Elem()
+	}
+	if structType.Kind() == reflect.Struct {
+		r.recordArrayFields(structType)
+	}
 	return r.nativeTypeProvider.registerType(reflect.TypeOf(refTyes))
 }
 
+// recordArrayFields records structType's fixed-size array fields (after tag
+// resolution and ",inline" flattening) so NewValue can validate that a CEL
+// list bound to one of them has exactly as many elements as the array
+// declares.
+func (r *Registry) recordArrayFields(structType reflect.Type) {
+	fields := make(map[string]reflect.Type)
+	for _, f := range flattenFields(structType, r.tagNames) {
+		if f.Field.Type.Kind() == reflect.Array {
+			fields[f.Tag.Name] = f.Field.Type
+		}
+	}
+	if len(fields) == 0 {
+		return
+	}
+	r.arrayFieldsMu.Lock()
+	r.arrayFields[typeName(structType)] = fields
+	r.arrayFieldsMu.Unlock()
+}
+
+// RegisterInterface registers a Go interface type together with the set of
+// concrete struct types that may implement it, so that CEL programs can
+// access fields on a value that is only known through the interface at the
+// Go level (e.g. a struct field typed as the interface, or a heterogeneous
+// slice of it). Each implementation must be identifiable by a type
+// discriminator, either a `TypeByte() string` method or a field tagged
+// `easycel:",type=<discriminator>"`.
+func (r *Registry) RegisterInterface(ifaceType reflect.Type, implementations ...any) error {
+	if ifaceType.Kind() != reflect.Interface {
+		return fmt.Errorf("%s is not an interface type", ifaceType.String())
+	}
+
+	impls := make(map[string]reflect.Type, len(implementations))
+	for _, impl := range implementations {
+		implType := reflect.TypeOf(impl)
+		for implType.Kind() == reflect.Pointer {
+			implType = implType.Elem()
+		}
+		if !implType.Implements(ifaceType) && !reflect.PointerTo(implType).Implements(ifaceType) {
+			return fmt.Errorf("%s does not implement %s", implType.String(), ifaceType.String())
+		}
+
+		discriminator, ok := r.discriminatorFor(implType)
+		if !ok {
+			return fmt.Errorf("%s has no type discriminator (add a TypeByte() method or a tagged field, e.g. %s)", implType.String(), r.discriminatorTagHint())
+		}
+		impls[discriminator] = implType
+
+		if err := r.RegisterType(reflect.New(implType).Interface()); err != nil {
+			return err
+		}
+	}
+
+	name := typeName(ifaceType)
+	r.interfaceMu.Lock()
+	r.interfaceImpls[ifaceType] = impls
+	r.interfaceNames[ifaceType] = name
+	r.interfaceByName[name] = ifaceType
+	r.interfaceMu.Unlock()
+	return nil
+}
+
+// FindInterfaceImplType resolves the concrete implementation of a registered
+// interface type for the given discriminator.
+func (r *Registry) FindInterfaceImplType(ifaceType reflect.Type, discriminator string) (reflect.Type, bool) {
+	r.interfaceMu.RLock()
+	defer r.interfaceMu.RUnlock()
+	implType, ok := r.interfaceImpls[ifaceType][discriminator]
+	return implType, ok
+}
+
+// discriminatorTagHint returns an example tagged-field form for error
+// messages, falling back to the default tag name if WithTagNames was called
+// with no tag names (leaving tagNames empty).
+func (r *Registry) discriminatorTagHint() string {
+	if len(r.tagNames) == 0 {
+		return `easycel:",type=..."`
+	}
+	return fmt.Sprintf("%s:\",type=...\"", r.tagNames[0])
+}
+
+// discriminatorFor returns the type discriminator used to identify implType
+// as a concrete implementation of a registered interface.
+func (r *Registry) discriminatorFor(implType reflect.Type) (string, bool) {
+	if implType.Implements(typeDiscriminatorType) {
+		return reflect.New(implType).Elem().Interface().(typeDiscriminator).TypeByte(), true
+	}
+	if reflect.PointerTo(implType).Implements(typeDiscriminatorType) {
+		return reflect.New(implType).Interface().(typeDiscriminator).TypeByte(), true
+	}
+	for i := 0; i < implType.NumField(); i++ {
+		for _, tagName := range r.tagNames {
+			tag, ok := implType.Field(i).Tag.Lookup(tagName)
+			if !ok {
+				continue
+			}
+			for _, part := range strings.Split(tag, ",") {
+				if discriminator, found := strings.CutPrefix(part, "type="); found {
+					return discriminator, true
+				}
+			}
+		}
+	}
+	return "", false
+}
+
 // RegisterVariable registers adapter value with the registry.
 func (r *Registry) RegisterVariable(name string, val interface{}) error {
 	if _, ok := r.variables[name]; ok {
 		return fmt.Errorf("variable %s already registered", name)
 	}
 	typ := reflect.TypeOf(val)
-	celType, ok := convertToCelType(typ)
+	celType, ok := r.convertToCelType(typ)
 	if !ok {
 		return fmt.Errorf("variable %s type %s not supported", name, typ.String())
 	}
@@ -152,12 +460,19 @@ func (r *Registry) RegisterVariable(name string, val interface{}) error {
 	return nil
 }
 
-// RegisterFunction registers adapter function with the registry.
+// RegisterFunction registers adapter function with the registry. If fun is
+// variadic, its overload declares the trailing parameter as a CEL list of
+// the variadic element type, so CEL callers must pass the variadic tail as
+// a single list argument (e.g. f(a, [x, y, z])) rather than spread
+// positional arguments (f(a, x, y, z)). A variadic ...byte parameter is the
+// exception: like any []byte it is declared as CEL bytes, and since a bytes
+// value is not a list, calls passing it fail at evaluation.
 func (r *Registry) RegisterFunction(name string, fun interface{}) error {
 	return r.registerFunction(name, fun, false)
 }
 
-// RegisterMethod registers adapter method with the registry.
+// RegisterMethod registers adapter method with the registry. See
+// RegisterFunction for the calling convention variadic methods require.
 func (r *Registry) RegisterMethod(name string, fun interface{}) error {
 	return r.registerFunction(name, fun, true)
 }
@@ -188,7 +503,7 @@ func (r *Registry) registerFunction(name string, fun interface{}, member bool) e
 	argsReflectType := make([]reflect.Type, 0, numIn)
 	for i := 0; i < numIn; i++ {
 		in := typ.In(i)
-		celType, ok := convertToCelType(in)
+		celType, ok := r.convertToCelType(in)
 		if !ok {
 			return fmt.Errorf("invalid input type %s", in.String())
 		}
@@ -197,7 +512,7 @@ func (r *Registry) registerFunction(name string, fun interface{}, member bool) e
 	}
 
 	out := typ.Out(0)
-	resultType, ok := convertToCelType(out)
+	resultType, ok := r.convertToCelType(out)
 	if !ok {
 		return fmt.Errorf("invalid output type %s", out.String())
 	}
@@ -246,63 +561,101 @@ func (r *Registry) getOverloadOpt(typ reflect.Type, funVal reflect.Value) (out c
 	}
 
 	numIn := typ.NumIn()
+	variadic := typ.IsVariadic()
 	isRefVal := make([]bool, numIn)
 	isPtr := make([]bool, numIn)
 	if numIn > 0 {
 		for i := 0; i < numIn; i++ {
 			in := typ.In(i)
+			if variadic && i == numIn-1 {
+				in = in.Elem()
+			}
 			isRefVal[i] = in.Implements(refValType)
 			isPtr[i] = in.Kind() == reflect.Ptr
 		}
 	}
 
+	// convertArg converts the i-th CEL argument to the reflect.Value expected
+	// at that position, collecting the trailing argument of a variadic func
+	// into a slice of its element type instead of a single value, and
+	// bridging the structpb dynamic JSON wrapper types from their CEL dyn
+	// representation. It returns an error instead of a zero value when that
+	// conversion fails, so a malformed structpb argument or a variadic
+	// argument that isn't a CEL list fails the CEL call rather than handing
+	// the target function a nil pointer or a silently empty slice.
+	convertArg := func(i int, value ref.Val) (reflect.Value, error) {
+		if variadic && i == numIn-1 {
+			return convertToVariadicSlice(value, typ.In(i), isRefVal[i], isPtr[i])
+		}
+		if isStructpbType(typ.In(i)) {
+			return valToStructpbType(value, typ.In(i))
+		}
+		return convertToReflectValue(value, isRefVal[i], isPtr[i]), nil
+	}
+
+	// toResult adapts a Go return value to adapter ref.Val, unwrapping the
+	// structpb dynamic JSON wrapper types to the plain Go values CEL's
+	// default adapter already knows how to convert.
+	toResult := func(val reflect.Value) ref.Val {
+		iface := val.Interface()
+		if isStructpbType(typ.Out(0)) {
+			iface = structpbAsInterface(iface)
+		}
+		return r.NativeToValue(iface)
+	}
+
 	switch numIn {
 	case 1:
 		return cel.UnaryBinding(func(value ref.Val) ref.Val {
-			val, err := reflectFuncCall(funVal,
-				[]reflect.Value{
-					convertToReflectValue(value, isRefVal[0], isPtr[0]),
-				},
-			)
+			arg0, err := convertArg(0, value)
 			if err != nil {
 				return types.WrapErr(err)
 			}
-			return r.NativeToValue(val.Interface())
+			val, err := reflectFuncCall(funVal, []reflect.Value{arg0}, variadic)
+			if err != nil {
+				return types.WrapErr(err)
+			}
+			return toResult(val)
 		}), nil
 	case 2:
 		return cel.BinaryBinding(func(lhs ref.Val, rhs ref.Val) ref.Val {
-			val, err := reflectFuncCall(funVal,
-				[]reflect.Value{
-					convertToReflectValue(lhs, isRefVal[0], isPtr[0]),
-					convertToReflectValue(rhs, isRefVal[1], isPtr[1]),
-				},
-			)
+			arg0, err := convertArg(0, lhs)
+			if err != nil {
+				return types.WrapErr(err)
+			}
+			arg1, err := convertArg(1, rhs)
+			if err != nil {
+				return types.WrapErr(err)
+			}
+			val, err := reflectFuncCall(funVal, []reflect.Value{arg0, arg1}, variadic)
 			if err != nil {
 				return types.WrapErr(err)
 			}
-			return r.NativeToValue(val.Interface())
+			return toResult(val)
 		}), nil
 	case 0:
 		return cel.FunctionBinding(func(values ...ref.Val) ref.Val {
-			val, err := reflectFuncCall(funVal, []reflect.Value{})
+			val, err := reflectFuncCall(funVal, []reflect.Value{}, false)
 			if err != nil {
 				return types.WrapErr(err)
 			}
-			return r.NativeToValue(val.Interface())
+			return toResult(val)
 		}), nil
 	default:
 		return cel.FunctionBinding(func(values ...ref.Val) ref.Val {
 			vals := make([]reflect.Value, 0, len(values))
 			for i, value := range values {
-				vals = append(vals,
-					convertToReflectValue(value, isRefVal[i], isPtr[i]),
-				)
+				arg, err := convertArg(i, value)
+				if err != nil {
+					return types.WrapErr(err)
+				}
+				vals = append(vals, arg)
 			}
-			val, err := reflectFuncCall(funVal, vals)
+			val, err := reflectFuncCall(funVal, vals, variadic)
 			if err != nil {
 				return types.WrapErr(err)
 			}
-			return r.NativeToValue(val.Interface())
+			return toResult(val)
 		}), nil
 	}
 }
@@ -326,8 +679,29 @@ func convertToReflectValue(val ref.Val, isRefVal, isPtr bool) reflect.Value {
 	return value
 }
 
-func reflectFuncCall(funVal reflect.Value, values []reflect.Value) (reflect.Value, error) {
-	results := funVal.Call(values)
+// convertToVariadicSlice converts a CEL list argument bound to the trailing
+// parameter of a variadic Go func into a reflect.Value slice of sliceType,
+// so it can be passed to reflect.Value.CallSlice.
+func convertToVariadicSlice(val ref.Val, sliceType reflect.Type, isRefVal, isPtr bool) (reflect.Value, error) {
+	lister, ok := val.(traits.Lister)
+	if !ok {
+		return reflect.Value{}, fmt.Errorf("cannot bind %T to variadic parameter %s: not a list", val.Value(), sliceType)
+	}
+	size := int(lister.Size().(types.Int))
+	slice := reflect.MakeSlice(sliceType, size, size)
+	for i := 0; i < size; i++ {
+		slice.Index(i).Set(convertToReflectValue(lister.Get(types.Int(i)), isRefVal, isPtr))
+	}
+	return slice, nil
+}
+
+func reflectFuncCall(funVal reflect.Value, values []reflect.Value, variadic bool) (reflect.Value, error) {
+	var results []reflect.Value
+	if variadic {
+		results = funVal.CallSlice(values)
+	} else {
+		results = funVal.Call(values)
+	}
 	if len(results) == 2 {
 		err, _ := results[1].Interface().(error)
 		if err != nil {
@@ -337,38 +711,130 @@ func reflectFuncCall(funVal reflect.Value, values []reflect.Value) (reflect.Valu
 	return results[0], nil
 }
 
-func fieldNameWithTag(field reflect.StructField, tagName string) (name string, exported bool) {
-	value, ok := field.Tag.Lookup(tagName)
-	if !ok {
-		return field.Name, true
+// fieldTag describes how a struct field should be surfaced as a CEL field,
+// once the tag fallback chain (e.g. "easycel", then "json", then "yaml")
+// has been resolved to a single tag value.
+type fieldTag struct {
+	Name      string
+	Exported  bool
+	OmitEmpty bool
+	Inline    bool
+	AsString  bool
+}
+
+// fieldNameWithTag resolves field's CEL-visible name and options by trying
+// each tag name in tagNames in order and using the first one present; if
+// none of them are present on the field, its Go name is used as-is.
+func fieldNameWithTag(field reflect.StructField, tagNames []string) fieldTag {
+	for _, tagName := range tagNames {
+		value, ok := field.Tag.Lookup(tagName)
+		if !ok {
+			continue
+		}
+		return parseFieldTagValue(field.Name, value)
 	}
+	return fieldTag{Name: field.Name, Exported: true}
+}
 
-	name = strings.Split(value, ",")[0]
+// parseFieldTagValue parses a single tag value (e.g. `foo,omitempty,string`)
+// following the same comma-separated conventions as encoding/json.
+func parseFieldTagValue(fieldName, value string) fieldTag {
+	parts := strings.Split(value, ",")
+	name := parts[0]
 	if name == "-" {
-		return "", false
+		return fieldTag{Exported: false}
 	}
-
 	if name == "" {
-		name = field.Name
+		name = fieldName
 	}
-	return name, true
+
+	tag := fieldTag{Name: name, Exported: true}
+	for _, opt := range parts[1:] {
+		switch opt {
+		case "omitempty":
+			tag.OmitEmpty = true
+		case "inline":
+			tag.Inline = true
+		case "string":
+			tag.AsString = true
+		}
+	}
+	return tag
 }
 
-func getFieldValue(adapter ref.TypeAdapter, refField reflect.Value) any {
+// getFieldValue returns the CEL-visible value for a struct field. When the
+// field's tag carries omitempty and the field holds its zero value, ok is
+// false and the field should be treated as absent (has() returns false)
+// instead of materializing an empty list, map, or struct.
+func getFieldValue(adapter types.Adapter, refField reflect.Value, tag fieldTag) (value any, ok bool) {
 	if refField.IsZero() {
+		if tag.OmitEmpty {
+			return nil, false
+		}
 		switch refField.Kind() {
 		case reflect.Array, reflect.Slice:
-			return types.NewDynamicList(adapter, []ref.Val{})
+			return types.NewDynamicList(adapter, []ref.Val{}), true
 		case reflect.Map:
-			return types.NewDynamicMap(adapter, map[ref.Val]ref.Val{})
+			return types.NewDynamicMap(adapter, map[ref.Val]ref.Val{}), true
 		case reflect.Struct:
 			if refField.Type() == timestampType {
-				return types.Timestamp{Time: time.Unix(0, 0)}
+				return types.Timestamp{Time: time.Unix(0, 0)}, true
 			}
-			return reflect.New(refField.Type()).Elem().Interface()
+			return reflect.New(refField.Type()).Elem().Interface(), true
 		case reflect.Pointer:
-			return reflect.New(refField.Type().Elem()).Interface()
+			return reflect.New(refField.Type().Elem()).Interface(), true
+		}
+	}
+	if refField.Kind() == reflect.Array {
+		if slice, ok := fastNumericArraySlice(refField); ok {
+			return applyFieldTag(slice.Interface(), refField.Type(), tag), true
+		}
+	}
+	return applyFieldTag(refField.Interface(), refField.Type(), tag), true
+}
+
+// applyFieldTag applies the ",string" tag option, exposing a numeric field
+// to CEL as its decimal string representation.
+func applyFieldTag(value any, refType reflect.Type, tag fieldTag) any {
+	if !tag.AsString {
+		return value
+	}
+	v := reflect.ValueOf(value)
+	switch refType.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, 64)
+	}
+	return value
+}
+
+// flattenedField is a struct field resolved through the ",inline" tag
+// option: embedded struct fields tagged inline are flattened into the
+// parent's CEL object namespace rather than nested under their own name.
+type flattenedField struct {
+	Field reflect.StructField
+	Tag   fieldTag
+}
+
+// flattenFields walks typ's fields, recursively flattening any field tagged
+// ",inline" so field discovery, FindFieldType, and NewValue all agree on the
+// same flat set of CEL field names for typ.
+func flattenFields(typ reflect.Type, tagNames []string) []flattenedField {
+	fields := make([]flattenedField, 0, typ.NumField())
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		tag := fieldNameWithTag(field, tagNames)
+		if !tag.Exported {
+			continue
+		}
+		if tag.Inline && field.Type.Kind() == reflect.Struct {
+			fields = append(fields, flattenFields(field.Type, tagNames)...)
+			continue
 		}
+		fields = append(fields, flattenedField{Field: field, Tag: tag})
 	}
-	return refField.Interface()
+	return fields
 }
@@ -0,0 +1,340 @@
+package easycel
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+)
+
+func TestParseFieldTagValue(t *testing.T) {
+	tag := parseFieldTagValue("Field", "name,omitempty,inline,string")
+	if tag.Name != "name" || !tag.OmitEmpty || !tag.Inline || !tag.AsString {
+		t.Fatalf("unexpected tag: %+v", tag)
+	}
+
+	if tag := parseFieldTagValue("Field", "-"); tag.Exported {
+		t.Fatalf("expected \"-\" to exclude the field, got %+v", tag)
+	}
+
+	if tag := parseFieldTagValue("Field", ""); tag.Name != "Field" || !tag.Exported {
+		t.Fatalf("expected empty tag to fall back to the Go field name, got %+v", tag)
+	}
+}
+
+type innerFlatten struct {
+	City string `easycel:"city"`
+}
+
+type outerFlatten struct {
+	Name  string       `easycel:"name"`
+	Inner innerFlatten `easycel:",inline"`
+	Skip  string       `easycel:"-"`
+}
+
+func TestFlattenFields(t *testing.T) {
+	fields := flattenFields(reflect.TypeOf(outerFlatten{}), []string{"easycel"})
+
+	names := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		names[f.Tag.Name] = true
+	}
+
+	if !names["name"] || !names["city"] {
+		t.Fatalf("expected inline flattening to expose \"city\" alongside \"name\", got %+v", names)
+	}
+	if names["Skip"] || names["-"] {
+		t.Fatalf("expected the \"-\" tagged field to be excluded, got %+v", names)
+	}
+	if names["Inner"] {
+		t.Fatalf("expected the inline field itself not to be exposed under its own name, got %+v", names)
+	}
+}
+
+func TestDiscriminatorTagHintNoPanic(t *testing.T) {
+	r := &Registry{tagNames: nil}
+	if hint := r.discriminatorTagHint(); hint == "" {
+		t.Fatal("expected a non-empty hint even with no configured tag names")
+	}
+
+	r = &Registry{tagNames: []string{"json"}}
+	if hint := r.discriminatorTagHint(); hint != `json:",type=..."` {
+		t.Fatalf("unexpected hint: %q", hint)
+	}
+}
+
+// shape is an interface with two heterogeneous implementations, used to
+// exercise interface polymorphism dispatch without going through the
+// native type provider.
+type shape interface {
+	Area() float64
+}
+
+type circle struct {
+	Radius float64 `easycel:"radius"`
+}
+
+func (circle) Area() float64   { return 0 }
+func (circle) TypeByte() string { return "circle" }
+
+type square struct {
+	Side float64 `easycel:"side"`
+}
+
+func (square) Area() float64    { return 0 }
+func (square) TypeByte() string { return "square" }
+
+var shapeType = reflect.TypeOf((*shape)(nil)).Elem()
+
+func TestDiscriminatorFor(t *testing.T) {
+	r := &Registry{tagNames: []string{"easycel"}}
+
+	discriminator, ok := r.discriminatorFor(reflect.TypeOf(circle{}))
+	if !ok || discriminator != "circle" {
+		t.Fatalf("expected TypeByte() discriminator %q, got %q (ok=%v)", "circle", discriminator, ok)
+	}
+
+	type tagged struct {
+		Kind string `easycel:",type=tagged"`
+	}
+	discriminator, ok = r.discriminatorFor(reflect.TypeOf(tagged{}))
+	if !ok || discriminator != "tagged" {
+		t.Fatalf("expected tagged-field discriminator %q, got %q (ok=%v)", "tagged", discriminator, ok)
+	}
+
+	type undiscriminated struct{}
+	if _, ok := r.discriminatorFor(reflect.TypeOf(undiscriminated{})); ok {
+		t.Fatal("expected no discriminator to be found")
+	}
+}
+
+func TestFindInterfaceImplType_Concurrent(t *testing.T) {
+	r := &Registry{
+		interfaceImpls: map[reflect.Type]map[string]reflect.Type{
+			shapeType: {
+				"circle": reflect.TypeOf(circle{}),
+				"square": reflect.TypeOf(square{}),
+			},
+		},
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if implType, ok := r.FindInterfaceImplType(shapeType, "circle"); !ok || implType != reflect.TypeOf(circle{}) {
+				t.Errorf("unexpected result for discriminator %q: %v, %v", "circle", implType, ok)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if implType, ok := r.FindInterfaceImplType(shapeType, "square"); !ok || implType != reflect.TypeOf(square{}) {
+		t.Fatalf("unexpected result for discriminator %q: %v, %v", "square", implType, ok)
+	}
+	if _, ok := r.FindInterfaceImplType(shapeType, "triangle"); ok {
+		t.Fatal("expected no implementation for an unregistered discriminator")
+	}
+}
+
+func TestFindStructType_Interface(t *testing.T) {
+	r := &Registry{interfaceByName: map[string]reflect.Type{"test.Shape": shapeType}}
+
+	typ, ok := r.FindStructType("test.Shape")
+	if !ok {
+		t.Fatal("expected a registered interface name to resolve")
+	}
+	if typ.TypeName() != "test.Shape" {
+		t.Fatalf("unexpected type name: %s", typ.TypeName())
+	}
+}
+
+// fakeFieldTypeProvider is a minimal types.Provider stand-in that only
+// implements FindStructFieldType and FindStructFieldNames, sufficient for
+// testing interface field dispatch without constructing a full native type
+// provider.
+type fakeFieldTypeProvider struct {
+	types.Provider
+	fields     map[string]*types.Type
+	fieldNames map[string][]string
+}
+
+func (f *fakeFieldTypeProvider) FindStructFieldType(structType, fieldName string) (*types.FieldType, bool) {
+	if fieldType, ok := f.fields[structType+"."+fieldName]; ok {
+		return &types.FieldType{Type: fieldType}, true
+	}
+	return nil, false
+}
+
+func (f *fakeFieldTypeProvider) FindStructFieldNames(structType string) ([]string, bool) {
+	names, ok := f.fieldNames[structType]
+	return names, ok
+}
+
+func TestFindStructFieldType_Interface(t *testing.T) {
+	r := &Registry{
+		interfaceByName: map[string]reflect.Type{"test.Shape": shapeType},
+		interfaceImpls: map[reflect.Type]map[string]reflect.Type{
+			shapeType: {
+				"circle": reflect.TypeOf(circle{}),
+				"square": reflect.TypeOf(square{}),
+			},
+		},
+		provider: &fakeFieldTypeProvider{
+			fields: map[string]*types.Type{
+				typeName(reflect.TypeOf(circle{})) + ".side": types.DoubleType,
+				typeName(reflect.TypeOf(square{})) + ".side": types.DoubleType,
+			},
+		},
+	}
+
+	if _, ok := r.FindStructFieldType("test.Shape", "side"); !ok {
+		t.Fatal("expected \"side\" to resolve since every implementation agrees on its type")
+	}
+	if _, ok := r.FindStructFieldType("test.Shape", "missing"); ok {
+		t.Fatal("expected an unknown field to not resolve on any implementation")
+	}
+}
+
+func TestFindStructFieldType_Interface_PartialImplementation(t *testing.T) {
+	r := &Registry{
+		interfaceByName: map[string]reflect.Type{"test.Shape": shapeType},
+		interfaceImpls: map[reflect.Type]map[string]reflect.Type{
+			shapeType: {
+				"circle": reflect.TypeOf(circle{}),
+				"square": reflect.TypeOf(square{}),
+			},
+		},
+		provider: &fakeFieldTypeProvider{
+			fields: map[string]*types.Type{
+				// Only square has "side" - circle doesn't, so a heterogeneous
+				// shape slice must not type-check a "side" access.
+				typeName(reflect.TypeOf(square{})) + ".side": types.DoubleType,
+			},
+		},
+	}
+
+	if _, ok := r.FindStructFieldType("test.Shape", "side"); ok {
+		t.Fatal("expected \"side\" not to resolve since circle doesn't implement it")
+	}
+}
+
+func TestFindStructFieldType_Interface_DisagreeingTypes(t *testing.T) {
+	r := &Registry{
+		interfaceByName: map[string]reflect.Type{"test.Shape": shapeType},
+		interfaceImpls: map[reflect.Type]map[string]reflect.Type{
+			shapeType: {
+				"circle": reflect.TypeOf(circle{}),
+				"square": reflect.TypeOf(square{}),
+			},
+		},
+		provider: &fakeFieldTypeProvider{
+			fields: map[string]*types.Type{
+				// Both implement "side", but as different CEL types.
+				typeName(reflect.TypeOf(circle{})) + ".side": types.StringType,
+				typeName(reflect.TypeOf(square{})) + ".side": types.DoubleType,
+			},
+		},
+	}
+
+	if _, ok := r.FindStructFieldType("test.Shape", "side"); ok {
+		t.Fatal("expected \"side\" not to resolve since circle and square disagree on its type")
+	}
+}
+
+func TestFindStructFieldNames_Interface(t *testing.T) {
+	r := &Registry{
+		interfaceByName: map[string]reflect.Type{"test.Shape": shapeType},
+		interfaceImpls: map[reflect.Type]map[string]reflect.Type{
+			shapeType: {
+				"circle": reflect.TypeOf(circle{}),
+				"square": reflect.TypeOf(square{}),
+			},
+		},
+		provider: &fakeFieldTypeProvider{
+			fieldNames: map[string][]string{
+				typeName(reflect.TypeOf(circle{})): {"radius"},
+				typeName(reflect.TypeOf(square{})): {"side"},
+			},
+		},
+	}
+
+	names, ok := r.FindStructFieldNames("test.Shape")
+	if !ok {
+		t.Fatal("expected field names to resolve for a registered interface")
+	}
+	seen := map[string]bool{}
+	for _, name := range names {
+		seen[name] = true
+	}
+	if !seen["radius"] || !seen["side"] {
+		t.Fatalf("expected the union of both implementations' field names, got %v", names)
+	}
+}
+
+func TestNewInterfaceValue_MissingDiscriminator(t *testing.T) {
+	r := &Registry{
+		interfaceImpls: map[reflect.Type]map[string]reflect.Type{
+			shapeType: {"circle": reflect.TypeOf(circle{})},
+		},
+	}
+	if val := r.newInterfaceValue(shapeType, map[string]ref.Val{}); !types.IsError(val) {
+		t.Fatalf("expected an error for a missing discriminator field, got %v", val)
+	}
+}
+
+func TestNewInterfaceValue_UnknownDiscriminator(t *testing.T) {
+	r := &Registry{
+		interfaceImpls: map[reflect.Type]map[string]reflect.Type{
+			shapeType: {"circle": reflect.TypeOf(circle{})},
+		},
+	}
+	fields := map[string]ref.Val{interfaceDiscriminatorField: types.String("triangle")}
+	if val := r.newInterfaceValue(shapeType, fields); !types.IsError(val) {
+		t.Fatalf("expected an error for an unregistered discriminator, got %v", val)
+	}
+}
+
+// fakeNewValueProvider is a minimal types.Provider stand-in that captures the
+// fields map passed to NewValue, sufficient for testing that the reserved
+// discriminator key never reaches the underlying provider.
+type fakeNewValueProvider struct {
+	types.Provider
+	gotFields map[string]ref.Val
+}
+
+func (f *fakeNewValueProvider) NewValue(typeName string, fields map[string]ref.Val) ref.Val {
+	f.gotFields = fields
+	return types.NewDynamicMap(types.DefaultTypeAdapter, map[string]any{})
+}
+
+func TestNewInterfaceValue_StripsDiscriminatorField(t *testing.T) {
+	provider := &fakeNewValueProvider{}
+	r := &Registry{
+		interfaceImpls: map[reflect.Type]map[string]reflect.Type{
+			shapeType: {"circle": reflect.TypeOf(circle{})},
+		},
+		provider: provider,
+	}
+	fields := map[string]ref.Val{
+		interfaceDiscriminatorField: types.String("circle"),
+		"radius":                    types.Double(1),
+	}
+
+	if val := r.newInterfaceValue(shapeType, fields); types.IsError(val) {
+		t.Fatalf("unexpected error: %v", val)
+	}
+
+	if _, ok := provider.gotFields[interfaceDiscriminatorField]; ok {
+		t.Fatalf("expected the discriminator field not to reach the underlying provider, got %v", provider.gotFields)
+	}
+	if _, ok := provider.gotFields["radius"]; !ok {
+		t.Fatalf("expected the remaining fields to still reach the underlying provider, got %v", provider.gotFields)
+	}
+	if _, ok := fields[interfaceDiscriminatorField]; !ok {
+		t.Fatal("expected the caller's fields map to be left untouched")
+	}
+}
@@ -1,18 +1,37 @@
 package easycel
 
 import (
+	"fmt"
 	"reflect"
 
 	"github.com/google/cel-go/cel"
-	"github.com/google/cel-go/checker/decls"
-	exprpb "google.golang.org/genproto/googleapis/api/expr/v1alpha1"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/common/types/traits"
+	"google.golang.org/protobuf/types/known/structpb"
 )
 
-// convertToCelType converts the Golang reflect.Type to CEL type
-func convertToCelType(refType reflect.Type) (*cel.Type, bool) {
+var (
+	structpbValueType     = reflect.TypeOf((*structpb.Value)(nil))
+	structpbStructType    = reflect.TypeOf((*structpb.Struct)(nil))
+	structpbListValueType = reflect.TypeOf((*structpb.ListValue)(nil))
+)
+
+// convertToCelType converts the Golang reflect.Type to CEL type, resolving
+// interface types registered via RegisterInterface to their named CEL
+// object type instead of the permissive dyn fallback.
+func (r *Registry) convertToCelType(refType reflect.Type) (*cel.Type, bool) {
+	switch refType {
+	case structpbValueType:
+		return cel.DynType, true
+	case structpbStructType:
+		return cel.MapType(cel.StringType, cel.DynType), true
+	case structpbListValueType:
+		return cel.ListType(cel.DynType), true
+	}
 	switch refType.Kind() {
 	case reflect.Pointer:
-		ptrType, ok := convertToCelType(refType.Elem())
+		ptrType, ok := r.convertToCelType(refType.Elem())
 		if !ok {
 			return nil, false
 		}
@@ -37,23 +56,27 @@ func convertToCelType(refType reflect.Type) (*cel.Type, bool) {
 		if refElem == byteType {
 			return cel.BytesType, true
 		}
-		elemType, ok := convertToCelType(refElem)
+		elemType, ok := r.convertToCelType(refElem)
 		if !ok {
 			return nil, false
 		}
 		return cel.ListType(elemType), true
 	case reflect.Array:
-		elemType, ok := convertToCelType(refType.Elem())
+		refElem := refType.Elem()
+		if refElem == byteType {
+			return cel.BytesType, true
+		}
+		elemType, ok := r.convertToCelType(refElem)
 		if !ok {
 			return nil, false
 		}
 		return cel.ListType(elemType), true
 	case reflect.Map:
-		keyType, ok := convertToCelType(refType.Key())
+		keyType, ok := r.convertToCelType(refType.Key())
 		if !ok {
 			return nil, false
 		}
-		elemType, ok := convertToCelType(refType.Elem())
+		elemType, ok := r.convertToCelType(refType.Elem())
 		if !ok {
 			return nil, false
 		}
@@ -64,63 +87,169 @@ func convertToCelType(refType reflect.Type) (*cel.Type, bool) {
 		}
 		return cel.ObjectType(typeName(refType)), true
 	case reflect.Interface:
+		r.interfaceMu.RLock()
+		name, ok := r.interfaceNames[refType]
+		r.interfaceMu.RUnlock()
+		if ok {
+			return cel.ObjectType(name), true
+		}
 		return cel.DynType, true
 	}
 	return nil, false
 }
 
-// convertToExprType converts the Golang reflect.Type to a protobuf exprpb.Type.
-func convertToExprType(refType reflect.Type) (*exprpb.Type, bool) {
-	switch refType.Kind() {
-	case reflect.Pointer:
-		return convertToExprType(refType.Elem())
-	case reflect.Bool:
-		return decls.Bool, true
-	case reflect.Float32, reflect.Float64:
-		return decls.Double, true
-	case reflect.Int64:
-		if refType == durationType || refType == typesDurationType {
-			return decls.Duration, true
+// ArrayLengthError is returned when a CEL list is bound to a fixed-size Go
+// array field with a different number of elements than the array declares.
+type ArrayLengthError struct {
+	Field    string
+	Declared int
+	Got      int
+}
+
+func (e *ArrayLengthError) Error() string {
+	return fmt.Sprintf("field %q is a [%d]array, got a list of %d elements", e.Field, e.Declared, e.Got)
+}
+
+// validateArrayLength checks that a CEL list about to be bound to an array
+// field has exactly as many elements as the array declares.
+func validateArrayLength(fieldName string, arrType reflect.Type, gotLen int) error {
+	if n := arrType.Len(); n != gotLen {
+		return &ArrayLengthError{Field: fieldName, Declared: n, Got: gotLen}
+	}
+	return nil
+}
+
+// fastNumericArraySlice exposes a fixed-size numeric array (e.g. [32]byte
+// hashes, [N]int32, [N]float64 ids) as a slice, avoiding an element-by-element
+// copy through reflect. If the array itself isn't addressable, it's copied
+// once into addressable storage first since only an addressable array value
+// can be sliced.
+func fastNumericArraySlice(refField reflect.Value) (reflect.Value, bool) {
+	switch refField.Type().Elem().Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+	default:
+		return reflect.Value{}, false
+	}
+
+	n := refField.Len()
+	if refField.CanAddr() {
+		return refField.Slice(0, n), true
+	}
+
+	// refField isn't addressable (e.g. it came from a map value); copy it
+	// once into addressable storage, which is itself already sliceable.
+	copyPtr := reflect.New(refField.Type())
+	copyPtr.Elem().Set(refField)
+	return copyPtr.Elem().Slice(0, n), true
+}
+
+// isStructpbType reports whether refType is one of the structpb dynamic JSON
+// wrapper types bridged directly to/from CEL dyn values.
+func isStructpbType(refType reflect.Type) bool {
+	switch refType {
+	case structpbValueType, structpbStructType, structpbListValueType:
+		return true
+	}
+	return false
+}
+
+// structpbAsInterface unwraps a structpb wrapper value into the plain Go
+// value that CEL's default type adapter already knows how to convert
+// (map[string]any, []any, string, float64, bool, nil).
+func structpbAsInterface(v any) any {
+	switch sv := v.(type) {
+	case *structpb.Value:
+		return sv.AsInterface()
+	case *structpb.Struct:
+		return sv.AsMap()
+	case *structpb.ListValue:
+		return sv.AsSlice()
+	}
+	return v
+}
+
+// valToNativeJSON converts a CEL ref.Val into the plain Go value tree
+// (map[string]any, []any, string, float64, bool, nil, ...) that
+// structpb.NewValue understands, by walking traits.Mapper/traits.Lister
+// generically instead of asserting val.Value() is already backed by exactly
+// map[string]any/[]any: CEL map/list literals (and most composite values)
+// are typically backed by map[ref.Val]ref.Val/[]ref.Val internally, so a
+// blind assertion fails for the common case.
+func valToNativeJSON(val ref.Val) (any, error) {
+	switch v := val.(type) {
+	case traits.Mapper:
+		m := make(map[string]any, v.Size().(types.Int))
+		it := v.Iterator()
+		for it.HasNext() == types.True {
+			keyVal := it.Next()
+			key, ok := keyVal.Value().(string)
+			if !ok {
+				return nil, fmt.Errorf("structpb conversion: map key %v is not a string", keyVal)
+			}
+			elemVal, found := v.Find(keyVal)
+			if !found {
+				return nil, fmt.Errorf("structpb conversion: missing value for key %q", key)
+			}
+			elem, err := valToNativeJSON(elemVal)
+			if err != nil {
+				return nil, err
+			}
+			m[key] = elem
 		}
-		return decls.Int, true
-	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32:
-		return decls.Int, true
-	case reflect.String:
-		return decls.String, true
-	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		return decls.Uint, true
-	case reflect.Slice:
-		refElem := refType.Elem()
-		if refElem == byteType {
-			return decls.Bytes, true
+		return m, nil
+	case traits.Lister:
+		size := int(v.Size().(types.Int))
+		s := make([]any, size)
+		for i := 0; i < size; i++ {
+			elem, err := valToNativeJSON(v.Get(types.Int(i)))
+			if err != nil {
+				return nil, err
+			}
+			s[i] = elem
 		}
-		elemType, ok := convertToExprType(refElem)
-		if !ok {
-			return nil, false
+		return s, nil
+	default:
+		return val.Value(), nil
+	}
+}
+
+// valToStructpbType converts a CEL ref.Val into the structpb wrapper type
+// matching refType (*structpb.Value, *structpb.Struct or *structpb.ListValue),
+// the inverse of structpbAsInterface, for dynamic JSON function parameters.
+func valToStructpbType(val ref.Val, refType reflect.Type) (reflect.Value, error) {
+	native, err := valToNativeJSON(val)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	switch refType {
+	case structpbValueType:
+		v, err := structpb.NewValue(native)
+		if err != nil {
+			return reflect.Value{}, err
 		}
-		return decls.NewListType(elemType), true
-	case reflect.Array:
-		refElem := refType.Elem()
-		elemType, ok := convertToExprType(refElem)
+		return reflect.ValueOf(v), nil
+	case structpbStructType:
+		m, ok := native.(map[string]any)
 		if !ok {
-			return nil, false
+			return reflect.Value{}, fmt.Errorf("cannot convert %s to *structpb.Struct", val.Type())
 		}
-		return decls.NewListType(elemType), true
-	case reflect.Map:
-		keyType, ok := convertToExprType(refType.Key())
-		if !ok {
-			return nil, false
+		s, err := structpb.NewStruct(m)
+		if err != nil {
+			return reflect.Value{}, err
 		}
-		elemType, ok := convertToExprType(refType.Elem())
+		return reflect.ValueOf(s), nil
+	case structpbListValueType:
+		l, ok := native.([]any)
 		if !ok {
-			return nil, false
+			return reflect.Value{}, fmt.Errorf("cannot convert %s to *structpb.ListValue", val.Type())
 		}
-		return decls.NewMapType(keyType, elemType), true
-	case reflect.Struct:
-		if refType == timestampType || refType == typesTimestampType {
-			return decls.Timestamp, true
+		lv, err := structpb.NewList(l)
+		if err != nil {
+			return reflect.Value{}, err
 		}
-		return decls.NewObjectType(typeName(refType)), true
+		return reflect.ValueOf(lv), nil
 	}
-	return nil, false
+	return reflect.Value{}, fmt.Errorf("not a structpb type: %s", refType)
 }
@@ -0,0 +1,206 @@
+package easycel
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestValidateArrayLength(t *testing.T) {
+	arrType := reflect.TypeOf([4]int32{})
+
+	if err := validateArrayLength("ids", arrType, 4); err != nil {
+		t.Fatalf("expected no error for matching length, got %v", err)
+	}
+
+	err := validateArrayLength("ids", arrType, 3)
+	if err == nil {
+		t.Fatal("expected an error for mismatched length")
+	}
+	arrErr, ok := err.(*ArrayLengthError)
+	if !ok {
+		t.Fatalf("expected *ArrayLengthError, got %T", err)
+	}
+	if arrErr.Field != "ids" || arrErr.Declared != 4 || arrErr.Got != 3 {
+		t.Fatalf("unexpected error fields: %+v", arrErr)
+	}
+}
+
+func TestFastNumericArraySlice(t *testing.T) {
+	arr := [4]int32{1, 2, 3, 4}
+	slice, ok := fastNumericArraySlice(reflect.ValueOf(&arr).Elem())
+	if !ok {
+		t.Fatal("expected fast path to apply to [4]int32")
+	}
+	got := slice.Interface().([]int32)
+	want := []int32{1, 2, 3, 4}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	type notNumeric [4]string
+	_, ok = fastNumericArraySlice(reflect.ValueOf(notNumeric{}))
+	if ok {
+		t.Fatal("expected fast path to reject non-numeric element types")
+	}
+}
+
+func TestConvertToCelType_ByteArray(t *testing.T) {
+	r := &Registry{}
+	celType, ok := r.convertToCelType(reflect.TypeOf([32]byte{}))
+	if !ok {
+		t.Fatal("expected [32]byte to convert")
+	}
+	if celType != cel.BytesType {
+		t.Fatalf("expected [32]byte to map to BytesType, got %v", celType)
+	}
+}
+
+func sum(nums ...int64) int64 {
+	var total int64
+	for _, n := range nums {
+		total += n
+	}
+	return total
+}
+
+func TestRegisterFunction_VariadicEndToEnd(t *testing.T) {
+	r := NewRegistry("test")
+	if err := r.RegisterFunction("sum", sum); err != nil {
+		t.Fatalf("unexpected error registering sum: %v", err)
+	}
+
+	env, err := cel.NewEnv(cel.Lib(r))
+	if err != nil {
+		t.Fatalf("unexpected error creating env: %v", err)
+	}
+
+	// The variadic tail is passed as a single list argument, not spread
+	// positional arguments, per the calling convention documented on
+	// RegisterFunction.
+	ast, issues := env.Compile("sum([1, 2, 3])")
+	if issues != nil && issues.Err() != nil {
+		t.Fatalf("unexpected compile error: %v", issues.Err())
+	}
+
+	program, err := env.Program(ast)
+	if err != nil {
+		t.Fatalf("unexpected error building program: %v", err)
+	}
+
+	out, _, err := program.Eval(cel.NoVars())
+	if err != nil {
+		t.Fatalf("unexpected eval error: %v", err)
+	}
+	if got := out.Value().(int64); got != 6 {
+		t.Fatalf("expected sum 6, got %d", got)
+	}
+}
+
+func countBytes(name string, data ...byte) int64 {
+	return int64(len(data))
+}
+
+func TestRegisterFunction_VariadicBytes_RejectsNonListArgument(t *testing.T) {
+	// A variadic ...byte parameter's trailing CEL type is bytes, and
+	// types.Bytes isn't a traits.Lister, so passing a bytes literal must
+	// fail the call instead of silently binding a zero-length slice.
+	r := NewRegistry("test")
+	if err := r.RegisterFunction("countBytes", countBytes); err != nil {
+		t.Fatalf("unexpected error registering countBytes: %v", err)
+	}
+
+	env, err := cel.NewEnv(cel.Lib(r))
+	if err != nil {
+		t.Fatalf("unexpected error creating env: %v", err)
+	}
+
+	ast, issues := env.Compile(`countBytes("x", b"abc")`)
+	if issues != nil && issues.Err() != nil {
+		t.Fatalf("unexpected compile error: %v", issues.Err())
+	}
+
+	program, err := env.Program(ast)
+	if err != nil {
+		t.Fatalf("unexpected error building program: %v", err)
+	}
+
+	if _, _, err := program.Eval(cel.NoVars()); err == nil {
+		t.Fatal("expected eval to fail instead of silently dropping the bytes argument")
+	}
+}
+
+func TestConvertToVariadicSliceAndReflectFuncCall(t *testing.T) {
+	funVal := reflect.ValueOf(sum)
+	sliceType := funVal.Type().In(0)
+
+	listVal := types.NewDynamicList(types.DefaultTypeAdapter, []ref.Val{types.Int(1), types.Int(2), types.Int(3)})
+	args, err := convertToVariadicSlice(listVal, sliceType, false, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result, err := reflectFuncCall(funVal, []reflect.Value{args}, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := result.Interface().(int64); got != 6 {
+		t.Fatalf("expected sum 6, got %d", got)
+	}
+}
+
+func TestConvertToVariadicSlice_NonListError(t *testing.T) {
+	funVal := reflect.ValueOf(sum)
+	sliceType := funVal.Type().In(0)
+
+	if _, err := convertToVariadicSlice(types.Int(1), sliceType, false, false); err == nil {
+		t.Fatal("expected an error for a non-list variadic argument")
+	}
+}
+
+func TestValToNativeJSON_RefValBackedMapAndList(t *testing.T) {
+	// A CEL map/list literal is typically backed by map[ref.Val]ref.Val and
+	// []ref.Val internally, not map[string]any/[]any directly, so the
+	// conversion must walk traits.Mapper/traits.Lister generically.
+	mapVal := types.NewDynamicMap(types.DefaultTypeAdapter, map[ref.Val]ref.Val{
+		types.String("name"): types.String("gopher"),
+	})
+	listVal := types.NewDynamicList(types.DefaultTypeAdapter, []ref.Val{types.Int(1), types.Int(2)})
+
+	m, err := valToNativeJSON(mapVal)
+	if err != nil {
+		t.Fatalf("unexpected error converting map: %v", err)
+	}
+	asMap, ok := m.(map[string]any)
+	if !ok || asMap["name"] != "gopher" {
+		t.Fatalf("unexpected map conversion result: %#v", m)
+	}
+
+	l, err := valToNativeJSON(listVal)
+	if err != nil {
+		t.Fatalf("unexpected error converting list: %v", err)
+	}
+	asSlice, ok := l.([]any)
+	if !ok || len(asSlice) != 2 {
+		t.Fatalf("unexpected list conversion result: %#v", l)
+	}
+}
+
+func TestValToStructpbType_RefValBackedStruct(t *testing.T) {
+	mapVal := types.NewDynamicMap(types.DefaultTypeAdapter, map[ref.Val]ref.Val{
+		types.String("name"): types.String("gopher"),
+	})
+
+	rv, err := valToStructpbType(mapVal, structpbStructType)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := rv.Interface().(*structpb.Struct).Fields["name"].GetStringValue()
+	if got != "gopher" {
+		t.Fatalf("expected field \"name\" to round-trip as \"gopher\", got %q", got)
+	}
+}